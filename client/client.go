@@ -1,26 +1,60 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"math/rand"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/donnybeelo/sanger-movie-server/internal/checkpoint"
+	"github.com/donnybeelo/sanger-movie-server/internal/config"
+	"github.com/donnybeelo/sanger-movie-server/internal/render"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 )
 
 var (
-	server   *string
-	port     *int
-	username *string
-	password *string
-	verbose  *bool
-	years    yearFlags
+	server         *string
+	port           *int
+	username       *string
+	password       *string
+	verbose        *bool
+	concurrency    *int
+	outputFmt      *string
+	daemon         *bool
+	pollInterval   *time.Duration
+	pollTimeout    *time.Duration
+	metricsAddr    *string
+	configPath     *string
+	profileName    *string
+	useTLS         *bool
+	caFile         *string
+	certFile       *string
+	keyFile        *string
+	insecureTLS    *bool
+	dumpMode       *bool
+	outFile        *string
+	resumeDump     *bool
+	checkpointFile *string
+	years          yearFlags
+	pins           pinFlags
 )
 
 // Custom flag type to handle multiple year arguments
@@ -45,6 +79,92 @@ func printVerbose(format string, v ...interface{}) {
 	}
 }
 
+// newHTTPClient builds the single, connection-pooled client shared by every
+// request the tool makes, so repeated calls to the same server reuse TCP
+// connections instead of paying a fresh handshake per page. tlsConfig may be
+// nil when TLS isn't in use.
+func newHTTPClient(tlsConfig *tls.Config) *http.Client {
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+		TLSClientConfig:     tlsConfig,
+	}
+	return &http.Client{Transport: transport}
+}
+
+// pinFlags collects repeated -pin arguments, each a "sha256:<hex>" SPKI pin.
+type pinFlags []string
+
+func (p *pinFlags) String() string {
+	return fmt.Sprintf("%v", *p)
+}
+
+func (p *pinFlags) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+// buildTLSConfig assembles the *tls.Config used for HTTPS connections,
+// wiring up a custom CA pool, an optional mTLS client certificate, and SPKI
+// pinning via VerifyPeerCertificate. If pins is non-empty, chain/hostname
+// verification is replaced entirely by the pin check, and the connection
+// fails closed if none of the presented certificates match a pin.
+func buildTLSConfig(caPath, certPath, keyPath string, insecure bool, pins []string) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: insecure}
+
+	if caPath != "" {
+		caCert, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %q: %w", caPath, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in CA file %q", caPath)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certPath != "" || keyPath != "" {
+		if certPath == "" || keyPath == "" {
+			return nil, fmt.Errorf("-cert and -key must both be set for mTLS client certificates")
+		}
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(pins) > 0 {
+		want := make(map[string]bool, len(pins))
+		for _, p := range pins {
+			want[strings.ToLower(strings.TrimPrefix(p, "sha256:"))] = true
+		}
+		// We verify the SPKI pin ourselves below, so skip Go's own chain
+		// verification rather than requiring both to pass.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			for _, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					continue
+				}
+				sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+				if want[hex.EncodeToString(sum[:])] {
+					return nil
+				}
+			}
+			return fmt.Errorf("no certificate in the chain matched any pinned SPKI hash")
+		}
+	}
+
+	return cfg, nil
+}
+
 // AuthRequest mirrors the JSON structure for the authentication request.
 type AuthRequest struct {
 	Username string `json:"username"`
@@ -56,16 +176,24 @@ type AuthResponse struct {
 	Bearer string `json:"bearer"`
 }
 
+// apiScheme returns "https" when tlsEnabled, otherwise "http".
+func apiScheme(tlsEnabled bool) string {
+	if tlsEnabled {
+		return "https"
+	}
+	return "http"
+}
+
 // authenticate handles logging into the server to get a bearer token.
-func authenticate(server string, port int, user, pass string) (string, error) {
+func authenticate(client *http.Client, tlsEnabled bool, server string, port int, user, pass string) (string, error) {
 	printVerbose("Connecting to server at %s:%d with username %s", server, port, user)
-	authURL := fmt.Sprintf("http://%s:%d/api/auth", server, port)
+	authURL := fmt.Sprintf("%s://%s:%d/api/auth", apiScheme(tlsEnabled), server, port)
 	reqBody, err := json.Marshal(AuthRequest{Username: user, Password: pass})
 	if err != nil {
 		return "", fmt.Errorf("failed to create auth request body: %w", err)
 	}
 
-	resp, err := http.Post(authURL, "application/json", bytes.NewBuffer(reqBody))
+	resp, err := client.Post(authURL, "application/json", bytes.NewBuffer(reqBody))
 	if err != nil {
 		return "", fmt.Errorf("failed to connect to server: %w", err)
 	}
@@ -88,113 +216,506 @@ func authenticate(server string, port int, user, pass string) (string, error) {
 	return authResp.Bearer, nil
 }
 
-// fetchMoviesInPage fetches movies for a single page and returns the count and status code.
-func fetchMoviesInPage(ctx context.Context, server string, port, year, page int, bearer string) (int, int, error) {
-	pageURL := fmt.Sprintf("http://%s:%d/api/movies/%d/%d", server, port, year, page)
+// fetchMoviesInPage fetches the raw movie records for a single page and
+// returns them along with the response status code. Callers that only need
+// a count (rather than the records themselves) take len(...) of the result.
+func fetchMoviesInPage(ctx context.Context, client *http.Client, tlsEnabled bool, server string, port, year, page int, bearer string) ([]json.RawMessage, int, error) {
+	pageURL := fmt.Sprintf("%s://%s:%d/api/movies/%d/%d", apiScheme(tlsEnabled), server, port, year, page)
 	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to create request for page %d: %w", page, err)
+		return nil, 0, fmt.Errorf("failed to create request for page %d: %w", page, err)
 	}
 	req.Header.Set("Authorization", "Bearer "+bearer)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		// Don't return an error if the context was canceled, as it's expected.
+		// Report context cancellation/deadline as an error rather than as
+		// status 0, which callers would otherwise treat the same as a 404
+		// (page doesn't exist) and silently undercount.
 		if ctx.Err() != nil {
-			return 0, 0, nil
+			return nil, 0, fmt.Errorf("failed to fetch page %d: %w", page, ctx.Err())
 		}
-		return 0, 0, fmt.Errorf("failed to fetch page %d: %w", page, err)
+		return nil, 0, fmt.Errorf("failed to fetch page %d: %w", page, err)
 	}
 	defer resp.Body.Close()
 
 	printVerbose("Fetching page %d for year %d: Status Code %d", page, year, resp.StatusCode)
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, resp.StatusCode, nil
+		return nil, resp.StatusCode, nil
 	}
 
-	var movies []interface{}
+	var movies []json.RawMessage
 	if err := json.NewDecoder(resp.Body).Decode(&movies); err != nil {
-		return 0, resp.StatusCode, fmt.Errorf("failed to decode movies response for page %d: %w", page, err)
+		return nil, resp.StatusCode, fmt.Errorf("failed to decode movies response for page %d: %w", page, err)
 	}
 
-	return len(movies), resp.StatusCode, nil
+	return movies, resp.StatusCode, nil
 }
 
-// fetchMoviesByYear orchestrates fetching all movies for a given year.
-// It first determines the number of movies on page 1, and if that is zero, returns zero.
-// It then finds the last page with movies. With the number of pages, it can calculate the total number of movies.
-func fetchMoviesByYear(server string, port, year int, bearer, user, pass string) (int, error) {
-	// Get movies on page 1 to find out movies per page.
-	moviesOnPage1, status, err := fetchMoviesInPage(context.Background(), server, port, year, 1, bearer)
+// recoveryWindow is the number of consecutive successful requests an
+// adaptiveLimiter requires before it grows its permit budget back up.
+const recoveryWindow = 20
+
+// adaptiveLimiter bounds the number of in-flight requests with a
+// semaphore.Weighted and shrinks that bound whenever the server signals it's
+// overloaded (429/5xx), recovering it gradually after a run of successes.
+// Shrinking is implemented by acquiring and permanently holding permits
+// rather than resizing the semaphore, since semaphore.Weighted has no resize
+// operation.
+type adaptiveLimiter struct {
+	sem *semaphore.Weighted
+	max int64
+
+	mu     sync.Mutex
+	held   int64
+	streak int
+}
+
+func newAdaptiveLimiter(max int64) *adaptiveLimiter {
+	return &adaptiveLimiter{sem: semaphore.NewWeighted(max), max: max}
+}
+
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	return l.sem.Acquire(ctx, 1)
+}
+
+func (l *adaptiveLimiter) release() {
+	l.sem.Release(1)
+}
+
+// throttle halves the current permit budget (max minus held-back permits,
+// down to a floor of one) and resets the recovery streak. The cut is taken
+// with TryAcquire, so when fewer permits than the cut are free at that
+// moment, the budget is left unchanged.
+func (l *adaptiveLimiter) throttle() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.streak = 0
+	available := l.max - l.held
+	if available <= 1 {
+		return
+	}
+	cut := available / 2
+	if cut < 1 {
+		cut = 1
+	}
+	if l.sem.TryAcquire(cut) {
+		l.held += cut
+	}
+}
+
+// recover credits one success toward restoring a held-back permit; once
+// recoveryWindow consecutive successes have been reported, a permit is
+// released back into circulation.
+func (l *adaptiveLimiter) recover() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == 0 {
+		return
+	}
+	l.streak++
+	if l.streak >= recoveryWindow {
+		l.streak = 0
+		l.held--
+		l.sem.Release(1)
+	}
+}
+
+// backoffWithJitter returns an exponential backoff duration for the given
+// retry attempt (0-indexed), with up to 50% random jitter to avoid thundering
+// herds across concurrently-retrying pages.
+func backoffWithJitter(attempt int) time.Duration {
+	base := time.Duration(math.Pow(2, float64(attempt))) * 100 * time.Millisecond
+	if base > 10*time.Second {
+		base = 10 * time.Second
+	}
+	return base + time.Duration(rand.Int63n(int64(base)/2+1))
+}
+
+// TokenSource caches a bearer token and refreshes it lazily on demand. It is
+// safe for concurrent use: callers that observe an expired token all race to
+// call Refresh, but only the first to arrive actually re-authenticates, and
+// the rest simply observe its result.
+type TokenSource struct {
+	client     *http.Client
+	tlsEnabled bool
+	server     string
+	port       int
+	user       string
+	pass       string
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewTokenSource builds a TokenSource; Token lazily performs the first
+// authentication the first time it's called.
+func NewTokenSource(client *http.Client, tlsEnabled bool, server string, port int, user, pass string) *TokenSource {
+	return &TokenSource{client: client, tlsEnabled: tlsEnabled, server: server, port: port, user: user, pass: pass}
+}
+
+// Token returns the cached bearer token, authenticating for the first time
+// if none is cached yet.
+func (ts *TokenSource) Token() (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token != "" {
+		return ts.token, nil
+	}
+	tok, err := authenticate(ts.client, ts.tlsEnabled, ts.server, ts.port, ts.user, ts.pass)
 	if err != nil {
-		return 0, fmt.Errorf("failed to fetch page 1 for year %d: %w", year, err)
+		return "", err
+	}
+	ts.token = tok
+	return ts.token, nil
+}
+
+// Refresh re-authenticates and caches a new token, unless another caller has
+// already replaced staleToken in the meantime.
+func (ts *TokenSource) Refresh(staleToken string) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token != staleToken {
+		return ts.token, nil
 	}
-	if status == http.StatusUnauthorized {
-		printVerbose("Session expired, re-authenticating...")
-		bearer, err = authenticate(server, port, user, pass)
+	printVerbose("Session expired, re-authenticating...")
+	tok, err := authenticate(ts.client, ts.tlsEnabled, ts.server, ts.port, ts.user, ts.pass)
+	if err != nil {
+		return "", fmt.Errorf("failed to re-authenticate: %w", err)
+	}
+	ts.token = tok
+	return ts.token, nil
+}
+
+// pageFetcher fetches a single page, transparently retrying on throttling
+// and re-authenticating on an expired bearer token. It's shared by every
+// concurrent probe issued for a given year.
+type pageFetcher struct {
+	client     *http.Client
+	tlsEnabled bool
+	limiter    *adaptiveLimiter
+	tokens     *TokenSource
+	server     string
+	port       int
+	year       int
+}
+
+func newPageFetcher(client *http.Client, tlsEnabled bool, limiter *adaptiveLimiter, tokens *TokenSource, server string, port, year int) *pageFetcher {
+	return &pageFetcher{client: client, tlsEnabled: tlsEnabled, limiter: limiter, tokens: tokens, server: server, port: port, year: year}
+}
+
+// fetch returns the movie count for page, i.e. len(fetchPage(...)).
+func (f *pageFetcher) fetch(ctx context.Context, page int) (int, error) {
+	movies, err := f.fetchPage(ctx, page)
+	if err != nil {
+		return 0, err
+	}
+	return len(movies), nil
+}
+
+// fetchPage returns the raw movie records for page, transparently retrying
+// on throttling and re-authenticating on an expired bearer token. A nil,
+// nil result means the page doesn't exist (e.g. the server answered 404).
+func (f *pageFetcher) fetchPage(ctx context.Context, page int) ([]json.RawMessage, error) {
+	for attempt := 0; ; attempt++ {
+		bearer, err := f.tokens.Token()
 		if err != nil {
-			return 0, fmt.Errorf("failed to re-authenticate: %w", err)
+			return nil, err
+		}
+		if err := f.limiter.acquire(ctx); err != nil {
+			return nil, err
 		}
-		moviesOnPage1, status, err = fetchMoviesInPage(context.Background(), server, port, year, 1, bearer)
+
+		movies, status, err := fetchMoviesInPage(ctx, f.client, f.tlsEnabled, f.server, f.port, f.year, page, bearer)
+		f.limiter.release()
 		if err != nil {
-			return 0, fmt.Errorf("failed to fetch page 1 for year %d after re-auth: %w", year, err)
+			return nil, err
 		}
-	}
 
-	if status != http.StatusOK {
-		return 0, fmt.Errorf("failed to fetch page 1 for year %d. Status: %d", year, status)
+		switch {
+		case status == http.StatusUnauthorized:
+			if _, err := f.tokens.Refresh(bearer); err != nil {
+				return nil, err
+			}
+		case status == http.StatusTooManyRequests || status >= 500:
+			f.limiter.throttle()
+			printVerbose("Page %d for year %d throttled (status %d), backing off", page, f.year, status)
+			select {
+			case <-time.After(backoffWithJitter(attempt)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		case status == http.StatusOK:
+			f.limiter.recover()
+			return movies, nil
+		default:
+			// Any other non-OK status (e.g. 404) means the page doesn't exist.
+			f.limiter.recover()
+			return nil, nil
+		}
 	}
+}
+
+// fetchMoviesByYear orchestrates fetching all movies for a given year.
+// It first determines the number of movies on page 1, which is also the
+// movies-per-page figure unless opts supplies an ExpectedPageSize. It then
+// speculatively fetches pages 1..k
+// concurrently, doubling k until an empty/non-OK page is seen, and
+// binary-searches within that last batch to pin down the exact last page.
+// With the number of pages, it can calculate the total number of movies.
+func fetchMoviesByYear(ctx context.Context, client *http.Client, tlsEnabled bool, limiter *adaptiveLimiter, tokens *TokenSource, server string, port, year int, opts config.YearOptions) (int, error) {
+	fetcher := newPageFetcher(client, tlsEnabled, limiter, tokens, server, port, year)
 
+	moviesOnPage1, err := fetcher.fetch(ctx, 1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch page 1 for year %d: %w", year, err)
+	}
 	if moviesOnPage1 == 0 {
 		return 0, nil
 	}
 	moviesPerPage := moviesOnPage1
+	if opts.ExpectedPageSize > 0 {
+		moviesPerPage = opts.ExpectedPageSize
+	}
 
-	// Find the last page
-	lastPage := 1
-	// Exponential search for an upper bound
+	// lastGood/lastGoodCount track the highest page confirmed non-empty so
+	// far and its actual movie count, so that a batch which comes back
+	// entirely empty doesn't lose track of a real count established by an
+	// earlier batch (or by the page-1 probe above).
+	lastGood := 1
+	lastGoodCount := moviesOnPage1
+	k := 1
 	for {
-		count, status, err := fetchMoviesInPage(context.Background(), server, port, year, lastPage*2, bearer)
+		lo, hi := k+1, k*2
+		counts, err := fetchBatch(ctx, fetcher, lo, hi)
 		if err != nil {
-			return 0, fmt.Errorf("failed to fetch page %d for year %d: %w", lastPage*2, year, err)
+			return 0, fmt.Errorf("failed probing pages %d-%d for year %d: %w", lo, hi, year, err)
 		}
-		if status != http.StatusOK || count == 0 {
-			break
+
+		fullBatch := true
+		for _, c := range counts {
+			if c == 0 {
+				fullBatch = false
+				break
+			}
 		}
-		lastPage *= 2
+		if fullBatch {
+			lastGood = hi
+			lastGoodCount = counts[len(counts)-1]
+			k = hi
+			continue
+		}
+
+		lastPage, moviesOnLastPage, err := binarySearchLastPage(ctx, fetcher, lastGood, lastGoodCount, hi, counts, lo)
+		if err != nil {
+			return 0, fmt.Errorf("failed to locate last page for year %d: %w", year, err)
+		}
+		return (lastPage-1)*moviesPerPage + moviesOnLastPage, nil
 	}
+}
 
-	// Binary search for the last page
-	low, high := lastPage, lastPage*2
+// fetchBatch concurrently fetches pages [lo, hi] and returns their counts in
+// page order, bounded by the fetcher's shared adaptiveLimiter.
+func fetchBatch(ctx context.Context, fetcher *pageFetcher, lo, hi int) ([]int, error) {
+	counts := make([]int, hi-lo+1)
+	g, gctx := errgroup.WithContext(ctx)
+	for p := lo; p <= hi; p++ {
+		p := p
+		g.Go(func() error {
+			count, err := fetcher.fetch(gctx, p)
+			if err != nil {
+				return err
+			}
+			counts[p-lo] = count
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// binarySearchLastPage narrows down the exact last non-empty page within
+// [lastGood+1, hi], given the counts already fetched for [batchLo, hi].
+// lastGood/lastGoodCount are the best page/count known prior to this batch,
+// used as the starting point when the batch itself contains no non-empty
+// pages (i.e. lastGood was already the true last page).
+func binarySearchLastPage(ctx context.Context, fetcher *pageFetcher, lastGood, lastGoodCount, hi int, counts []int, batchLo int) (int, int, error) {
+	pages := make([]int, 0, len(counts))
+	for i, c := range counts {
+		if c > 0 {
+			pages = append(pages, batchLo+i)
+		}
+	}
+	sort.Ints(pages)
+
+	lastPage := lastGood
+	lastCount := lastGoodCount
+	if len(pages) > 0 {
+		lastPage = pages[len(pages)-1]
+		lastCount = counts[lastPage-batchLo]
+	}
+
+	low, high := lastPage, hi
 	for low <= high {
 		mid := (low + high) / 2
-		if mid == 0 { // Should not happen with our logic
-			break
+		if mid == lastPage {
+			low = mid + 1
+			continue
 		}
-		count, status, err := fetchMoviesInPage(context.Background(), server, port, year, mid, bearer)
+		count, err := fetcher.fetch(ctx, mid)
 		if err != nil {
-			return 0, fmt.Errorf("failed to fetch page %d for year %d: %w", mid, year, err)
+			return 0, 0, fmt.Errorf("failed to fetch page %d: %w", mid, err)
 		}
-		if status == http.StatusOK && count > 0 {
-			lastPage = mid
+		if count > 0 {
+			lastPage, lastCount = mid, count
 			low = mid + 1
 		} else {
 			high = mid - 1
 		}
 	}
 
-	// Get the number of movies on the last page
-	moviesOnLastPage, status, err := fetchMoviesInPage(context.Background(), server, port, year, lastPage, bearer)
-	if err != nil {
-		return 0, fmt.Errorf("failed to fetch last page %d for year %d: %w", lastPage, year, err)
+	return lastPage, lastCount, nil
+}
+
+// ndjsonWriter wraps the dump output in a bufio.Writer. It's written to by a
+// single dedicated goroutine (see runNDJSONWriter), so it needs no locking of
+// its own.
+type ndjsonWriter struct {
+	bw *bufio.Writer
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{bw: bufio.NewWriter(w)}
+}
+
+// writeAll writes one line per record and flushes, so that when it returns
+// nil the whole page has reached the underlying writer. A page larger than
+// the bufio buffer can still be partly written if a write fails part-way.
+func (n *ndjsonWriter) writeAll(records []json.RawMessage) error {
+	for _, r := range records {
+		if _, err := n.bw.Write(r); err != nil {
+			return err
+		}
+		if err := n.bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return n.bw.Flush()
+}
+
+// dumpChunk is one page's worth of records, streamed from a per-year dumping
+// goroutine to the single writer goroutine. result carries back the write
+// outcome so the sending goroutine can checkpoint only once the page is
+// actually on disk.
+type dumpChunk struct {
+	records []json.RawMessage
+	result  chan<- error
+}
+
+// runNDJSONWriter is the single goroutine that owns out, serializing writes
+// from every concurrently-dumping year so that lines from one page are never
+// interleaved with another's.
+func runNDJSONWriter(out *ndjsonWriter, chunks <-chan dumpChunk) {
+	for chunk := range chunks {
+		chunk.result <- out.writeAll(chunk.records)
 	}
-	if status != http.StatusOK {
-		return 0, fmt.Errorf("failed to fetch last page %d for year %d. Status: %d", lastPage, year, status)
+}
+
+// dumpMoviesByYear walks every page for year, streaming each page's movie
+// records through chunks to the single writer goroutine. If cp is non-nil,
+// pages at or below its recorded checkpoint for this year are skipped, and
+// each page written advances the checkpoint, so an interrupted dump can
+// resume without re-fetching pages.
+func dumpMoviesByYear(ctx context.Context, client *http.Client, tlsEnabled bool, limiter *adaptiveLimiter, tokens *TokenSource, server string, port, year int, chunks chan<- dumpChunk, cp *checkpoint.Store) error {
+	fetcher := newPageFetcher(client, tlsEnabled, limiter, tokens, server, port, year)
+
+	startPage := 1
+	if cp != nil {
+		startPage = cp.LastPage(year) + 1
+	}
+
+	for page := startPage; ; page++ {
+		movies, err := fetcher.fetchPage(ctx, page)
+		if err != nil {
+			return fmt.Errorf("failed to fetch page %d for year %d: %w", page, year, err)
+		}
+		if len(movies) == 0 {
+			return nil
+		}
+
+		result := make(chan error, 1)
+		chunks <- dumpChunk{records: movies, result: result}
+		if err := <-result; err != nil {
+			return fmt.Errorf("failed to write page %d for year %d: %w", page, year, err)
+		}
+
+		if cp != nil {
+			if err := cp.MarkDone(year, page); err != nil {
+				return fmt.Errorf("failed to checkpoint page %d for year %d: %w", page, year, err)
+			}
+		}
+	}
+}
+
+// runDump drives --dump mode: it walks every page for each requested year
+// concurrently, streaming every movie record through a channel to a single
+// writer goroutine, which writes it to outPath (or stdout) as NDJSON,
+// optionally resuming from a checkpoint file.
+func runDump(client *http.Client, tlsEnabled bool, tokens *TokenSource, server string, port, concurrency int, years []int, outPath string, resume bool, checkpointPath string) {
+	w := io.Writer(os.Stdout)
+	if outPath != "" {
+		// On resume, append to the earlier output rather than truncating
+		// the pages the checkpoint says are already written.
+		openFlags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if resume {
+			openFlags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+		f, err := os.OpenFile(outPath, openFlags, 0644)
+		if err != nil {
+			log.Fatalf("Failed to create output file %q: %v", outPath, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	out := newNDJSONWriter(w)
+
+	var cp *checkpoint.Store
+	if resume {
+		var err error
+		cp, err = checkpoint.Load(checkpointPath)
+		if err != nil {
+			log.Fatalf("Failed to load checkpoint file: %v", err)
+		}
 	}
 
-	totalMovies := (lastPage-1)*moviesPerPage + moviesOnLastPage
-	return totalMovies, nil
+	// A single limiter is shared across every year so that -c bounds total
+	// in-flight requests globally, not per year.
+	limiter := newAdaptiveLimiter(int64(concurrency))
+
+	chunks := make(chan dumpChunk)
+	var writerWg sync.WaitGroup
+	writerWg.Add(1)
+	go func() {
+		defer writerWg.Done()
+		runNDJSONWriter(out, chunks)
+	}()
+
+	var wg sync.WaitGroup
+	for _, year := range years {
+		wg.Add(1)
+		go func(y int) {
+			defer wg.Done()
+			if err := dumpMoviesByYear(context.Background(), client, tlsEnabled, limiter, tokens, server, port, y, chunks, cp); err != nil {
+				log.Printf("Failed to dump movies for year %d: %v", y, err)
+			}
+		}(year)
+	}
+	wg.Wait()
+	close(chunks)
+	writerWg.Wait()
 }
 
 func main() {
@@ -204,6 +725,25 @@ func main() {
 	password = flag.String("p", "", "Password for authentication (required)")
 	flag.Var(&years, "Y", "Filter movie database by year (required, can be repeated)")
 	verbose = flag.Bool("v", false, "Enable verbose output")
+	concurrency = flag.Int("c", 8, "Maximum number of concurrent in-flight page requests, shared across all years")
+	outputFmt = flag.String("o", "text", "Output format: text, json, csv, or prom")
+	daemon = flag.Bool("d", false, "Run as a long-lived daemon, periodically re-fetching counts and serving them over HTTP")
+	flag.BoolVar(daemon, "daemon", false, "Long form of -d")
+	pollInterval = flag.Duration("interval", 5*time.Minute, "Polling interval in daemon mode (e.g. 30s, 5m, 1h)")
+	pollTimeout = flag.Duration("poll-timeout", 0, "Deadline for a single poll cycle in daemon mode (default: 4x -interval, so a cycle slowed by backoff isn't cut off right when the server is struggling)")
+	metricsAddr = flag.String("metrics-addr", ":9090", "Address to serve /metrics and /healthz on in daemon mode")
+	configPath = flag.String("config", "", "Path to a YAML config file defining server profiles and per-year options")
+	profileName = flag.String("profile", "", "Config profile to use (default: the config file's default_profile, or its only profile)")
+	useTLS = flag.Bool("tls", false, "Use HTTPS instead of HTTP to talk to the server")
+	caFile = flag.String("ca", "", "Path to a PEM CA bundle to trust, in addition to the system roots")
+	certFile = flag.String("cert", "", "Path to a PEM client certificate for mTLS (requires -key)")
+	keyFile = flag.String("key", "", "Path to the PEM private key matching -cert (requires -cert)")
+	insecureTLS = flag.Bool("insecure", false, "Skip TLS certificate verification (not recommended)")
+	flag.Var(&pins, "pin", "Pin a server certificate by its SPKI SHA-256 hash (sha256:<hex>), can be repeated")
+	dumpMode = flag.Bool("dump", false, "Stream every movie record as NDJSON instead of reporting counts")
+	outFile = flag.String("out", "", "Write --dump output to this file instead of stdout")
+	resumeDump = flag.Bool("resume", false, "In --dump mode, skip pages already written according to -checkpoint-file")
+	checkpointFile = flag.String("checkpoint-file", "sanger-dump.checkpoint.json", "Path to the --resume checkpoint file")
 
 	// Custom parsing to allow single-dash long options like Python's argparse
 	err := parseFlags(os.Args[1:])
@@ -212,48 +752,190 @@ func main() {
 		flag.Usage()
 		os.Exit(1)
 	}
+	explicit := explicitFlags()
+
+	var cfg *config.Config
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+	}
+	profile, err := cfg.Profile(*profileName)
+	if err != nil {
+		log.Fatalf("Failed to resolve config profile: %v", err)
+	}
+
+	// Precedence: flags > env vars > config file > defaults.
+	resolvedServer := resolveString(explicit, "s", *server, "SANGER_SERVER", profile.Host)
+	resolvedPort := resolveInt(explicit, "P", *port, "SANGER_PORT", profile.Port)
+	resolvedUser := resolveString(explicit, "u", *username, "SANGER_USER", profile.Username)
+	rawPassword := resolveString(explicit, "p", *password, "SANGER_PASSWORD", profile.Password)
+	resolvedPassword, err := resolvePassword(rawPassword)
+	if err != nil {
+		log.Fatalf("Failed to resolve password: %v", err)
+	}
+	*server, *port, *username, *password = resolvedServer, resolvedPort, resolvedUser, resolvedPassword
+	*useTLS = resolveBool(explicit, "tls", *useTLS, profile.TLS)
+	*caFile = resolveString(explicit, "ca", *caFile, "", profile.CAFile)
+	*certFile = resolveString(explicit, "cert", *certFile, "", profile.CertFile)
+	*keyFile = resolveString(explicit, "key", *keyFile, "", profile.KeyFile)
+	*insecureTLS = resolveBool(explicit, "insecure", *insecureTLS, profile.Insecure)
+	if !explicit["pin"] && len(profile.Pins) > 0 {
+		pins = profile.Pins
+	}
 
 	if *server == "" || *username == "" || *password == "" || len(years) == 0 {
 		flag.Usage()
 		os.Exit(1)
 	}
-
-	bearer, err := authenticate(*server, *port, *username, *password)
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
+	renderer, err := render.New(*outputFmt)
 	if err != nil {
+		log.Fatalf("Invalid output format: %v", err)
+	}
+
+	var tlsConfig *tls.Config
+	if *useTLS {
+		tlsConfig, err = buildTLSConfig(*caFile, *certFile, *keyFile, *insecureTLS, pins)
+		if err != nil {
+			log.Fatalf("Failed to configure TLS: %v", err)
+		}
+	}
+
+	client := newHTTPClient(tlsConfig)
+	tokens := NewTokenSource(client, *useTLS, *server, *port, *username, *password)
+	if _, err := tokens.Token(); err != nil {
 		log.Fatalf("Authentication failed: %v", err)
 	}
 
 	printVerbose("Filtering movies by year(s): %s", strings.Trim(strings.Join(strings.Fields(fmt.Sprint(years)), ", "), "[]"))
 
-	var wg sync.WaitGroup
-	results := make(map[int]int)
-	var mu sync.Mutex
+	if *dumpMode {
+		runDump(client, *useTLS, tokens, *server, *port, *concurrency, years, *outFile, *resumeDump, *checkpointFile)
+		return
+	}
 
-	for _, year := range years {
-		wg.Add(1)
-		go func(y int) {
-			defer wg.Done()
-			count, err := fetchMoviesByYear(*server, *port, y, bearer, *username, *password)
-			if err != nil {
-				log.Printf("Failed to fetch movies for year %d: %v", y, err)
-				return
+	// A single limiter is shared across every year (and, in daemon mode,
+	// across every poll cycle) so that -c bounds total in-flight requests
+	// globally, not per year.
+	limiter := newAdaptiveLimiter(int64(*concurrency))
+
+	fetchAll := func(ctx context.Context) []render.Record {
+		var wg sync.WaitGroup
+		results := make(map[int]int)
+		var mu sync.Mutex
+
+		for _, year := range years {
+			wg.Add(1)
+			go func(y int) {
+				defer wg.Done()
+				opts := cfg.YearOptionsFor(y)
+				count, err := fetchMoviesByYear(ctx, client, *useTLS, limiter, tokens, *server, *port, y, opts)
+				if err != nil {
+					log.Printf("Failed to fetch movies for year %d: %v", y, err)
+					return
+				}
+				if count == 0 && opts.IgnoreIfEmpty {
+					return
+				}
+				mu.Lock()
+				results[y] = count
+				mu.Unlock()
+			}(year)
+		}
+		wg.Wait()
+
+		fetchedAt := time.Now()
+		records := make([]render.Record, 0, len(years))
+		for _, year := range years {
+			if count, ok := results[year]; ok {
+				records = append(records, render.Record{Year: year, Count: count, FetchedAt: fetchedAt})
 			}
-			mu.Lock()
-			results[y] = count
-			mu.Unlock()
-		}(year)
+		}
+		return records
 	}
 
-	wg.Wait()
+	if !*daemon {
+		if err := renderer.Render(os.Stdout, fetchAll(context.Background())); err != nil {
+			log.Fatalf("Failed to render results: %v", err)
+		}
+		return
+	}
 
-	for _, year := range years {
-		if count, ok := results[year]; ok {
-			suffix := "s"
-			if count == 1 {
-				suffix = ""
-			}
-			fmt.Printf("Year %d: %d movie%s\n", year, count, suffix)
+	timeout := *pollTimeout
+	if timeout <= 0 {
+		timeout = 4 * *pollInterval
+	}
+	runDaemon(fetchAll, *pollInterval, timeout, *metricsAddr)
+}
+
+// latestResults holds the most recent poll's records, guarded by a mutex
+// since it's written by the poll loop and read concurrently by HTTP
+// handlers.
+type latestResults struct {
+	mu      sync.RWMutex
+	records []render.Record
+}
+
+func (l *latestResults) set(records []render.Record) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = records
+}
+
+func (l *latestResults) get() []render.Record {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.records
+}
+
+// runDaemon polls fetchAll every interval, serving the latest results over
+// HTTP at /metrics (Prometheus format) and /healthz. It blocks forever.
+//
+// Each poll cycle's requests are bounded by timeout rather than by interval
+// itself: reusing the poll interval as the request deadline means a cycle
+// slowed by throttling/backoff gets cut off right as the server is already
+// struggling, and a cancellation deep in the fetch chain would otherwise be
+// indistinguishable from a year legitimately having no movies.
+func runDaemon(fetchAll func(ctx context.Context) []render.Record, interval, timeout time.Duration, addr string) {
+	var latest latestResults
+	promRenderer := render.PromRenderer{}
+
+	poll := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		records := fetchAll(ctx)
+		latest.set(records)
+		printVerbose("Poll complete: %d year(s) refreshed", len(records))
+	}
+	poll()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := promRenderer.Render(w, latest.get()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+
+	go func() {
+		log.Printf("Serving metrics on %s", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Fatalf("Metrics server failed: %v", err)
 		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		poll()
 	}
 }
 
@@ -273,3 +955,79 @@ func parseFlags(args []string) error {
 	}
 	return flag.CommandLine.Parse(newArgs)
 }
+
+// explicitFlags returns the set of flag names that were explicitly provided
+// on the command line, as opposed to left at their default value.
+func explicitFlags() map[string]bool {
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// resolveString applies the flags > env vars > config file > defaults
+// precedence for a single string-valued setting.
+func resolveString(explicit map[string]bool, flagName, flagValue, envVar, configValue string) string {
+	if explicit[flagName] {
+		return flagValue
+	}
+	if v := os.Getenv(envVar); v != "" {
+		return v
+	}
+	if configValue != "" {
+		return configValue
+	}
+	return flagValue
+}
+
+// resolveInt is resolveString for int-valued settings; a zero configValue is
+// treated as "unset" since 0 is never a valid port.
+func resolveInt(explicit map[string]bool, flagName string, flagValue int, envVar string, configValue int) int {
+	if explicit[flagName] {
+		return flagValue
+	}
+	if v := os.Getenv(envVar); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	if configValue != 0 {
+		return configValue
+	}
+	return flagValue
+}
+
+// resolveBool is resolveString for bool-valued settings with no env var:
+// flags > config file > default. A true configValue wins over an
+// unexplicit false flagValue, since false is indistinguishable from "unset"
+// for a bool flag.
+func resolveBool(explicit map[string]bool, flagName string, flagValue, configValue bool) bool {
+	if explicit[flagName] {
+		return flagValue
+	}
+	if configValue {
+		return true
+	}
+	return flagValue
+}
+
+// resolvePassword expands the -p flag's special forms: "@path" reads the
+// password from a file, and "-" reads a single line from stdin, so the
+// secret never has to appear in the process argument list.
+func resolvePassword(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "@"):
+		data, err := os.ReadFile(raw[1:])
+		if err != nil {
+			return "", fmt.Errorf("failed to read password file %q: %w", raw[1:], err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case raw == "-":
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+		return strings.TrimSpace(line), nil
+	default:
+		return raw, nil
+	}
+}
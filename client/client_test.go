@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/donnybeelo/sanger-movie-server/internal/config"
+)
+
+func init() {
+	// printVerbose dereferences this flag var, which main() normally sets
+	// up via flag.Bool; tests don't go through main, so set it directly.
+	off := false
+	verbose = &off
+}
+
+// newTestMovieServer serves pageSize movies per page up to totalMovies
+// total, 404ing once a page runs past the end, and accepts any credentials.
+func newTestMovieServer(t *testing.T, pageSize, totalMovies int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(AuthResponse{Bearer: "test-token"})
+	})
+	mux.HandleFunc("/api/movies/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/movies/"), "/")
+		if len(parts) != 2 {
+			http.NotFound(w, r)
+			return
+		}
+		page, err := strconv.Atoi(parts[1])
+		if err != nil || page < 1 {
+			http.NotFound(w, r)
+			return
+		}
+
+		start := (page - 1) * pageSize
+		if start >= totalMovies {
+			http.NotFound(w, r)
+			return
+		}
+		end := start + pageSize
+		if end > totalMovies {
+			end = totalMovies
+		}
+
+		movies := make([]int, end-start)
+		json.NewEncoder(w).Encode(movies)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestFetchMoviesByYearPageBoundaries(t *testing.T) {
+	cases := []struct {
+		name        string
+		pageSize    int
+		totalMovies int
+	}{
+		{"single partial page", 5, 3},
+		{"exact doubling boundary", 5, 20},
+		{"partial last page after full batches", 5, 23},
+		{"no movies", 5, 0},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestMovieServer(t, tc.pageSize, tc.totalMovies)
+			defer srv.Close()
+
+			host, portStr, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+			if err != nil {
+				t.Fatalf("failed to parse test server address: %v", err)
+			}
+			port, err := strconv.Atoi(portStr)
+			if err != nil {
+				t.Fatalf("failed to parse test server port: %v", err)
+			}
+
+			client := srv.Client()
+			tokens := NewTokenSource(client, false, host, port, "user", "pass")
+			limiter := newAdaptiveLimiter(4)
+
+			count, err := fetchMoviesByYear(context.Background(), client, false, limiter, tokens, host, port, 2023, config.YearOptions{})
+			if err != nil {
+				t.Fatalf("fetchMoviesByYear() returned error: %v", err)
+			}
+			if count != tc.totalMovies {
+				t.Errorf("fetchMoviesByYear() = %d, want %d", count, tc.totalMovies)
+			}
+		})
+	}
+}
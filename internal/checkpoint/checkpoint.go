@@ -0,0 +1,67 @@
+// Package checkpoint persists per-year dump progress to disk so a
+// `--dump --resume` run can skip pages it has already written.
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Store is the on-disk record of the last page fully written for each year.
+type Store struct {
+	path string
+
+	mu   sync.Mutex
+	data map[int]int
+}
+
+// Load reads the checkpoint file at path, or returns an empty Store if it
+// doesn't exist yet.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, data: make(map[int]int)}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file %q: %w", path, err)
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint file %q: %w", path, err)
+	}
+	return s, nil
+}
+
+// LastPage returns the last page known to be fully written for year, or 0 if
+// no pages have been recorded yet.
+func (s *Store) LastPage(year int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data[year]
+}
+
+// MarkDone records that page is the last fully-written page for year, and
+// persists the checkpoint file.
+func (s *Store) MarkDone(year, page int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data[year] >= page {
+		return nil
+	}
+	s.data[year] = page
+	return s.save()
+}
+
+func (s *Store) save() error {
+	raw, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	if err := os.WriteFile(s.path, raw, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint file %q: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Package config loads the optional configuration file that lets users
+// define multiple named server profiles and per-year fetch overrides
+// instead of passing the same flags on every invocation.
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ServerProfile describes one named Sanger server to connect to, including
+// the TLS settings needed to reach it (see the -tls/-ca/-cert/-key/-insecure
+// /-pin flags, which a profile can supply instead).
+type ServerProfile struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	TLS      bool     `yaml:"tls"`
+	CAFile   string   `yaml:"ca_file"`
+	CertFile string   `yaml:"cert_file"`
+	KeyFile  string   `yaml:"key_file"`
+	Insecure bool     `yaml:"insecure"`
+	Pins     []string `yaml:"pins"`
+}
+
+// YearOptions holds per-year overrides for fetching behaviour.
+type YearOptions struct {
+	// ExpectedPageSize, when the server's page size is known, replaces the
+	// movies-per-page value otherwise taken from page 1. Page 1 is still
+	// fetched either way.
+	ExpectedPageSize int `yaml:"expected_page_size"`
+	// IgnoreIfEmpty leaves a year with zero movies out of the results
+	// entirely, instead of reporting it with a count of 0.
+	IgnoreIfEmpty bool `yaml:"ignore_if_empty"`
+}
+
+// Config is the parsed contents of a -config file.
+type Config struct {
+	DefaultProfile string                   `yaml:"default_profile"`
+	Profiles       map[string]ServerProfile `yaml:"profiles"`
+	Years          map[int]YearOptions      `yaml:"years"`
+}
+
+// Load reads and parses the YAML config file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %q: %w", path, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Profile returns the named profile. If name is empty, it falls back to the
+// file's default_profile, and then to its only profile if there's exactly
+// one.
+func (c *Config) Profile(name string) (ServerProfile, error) {
+	if c == nil {
+		return ServerProfile{}, nil
+	}
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		if len(c.Profiles) != 1 {
+			return ServerProfile{}, nil
+		}
+		for _, p := range c.Profiles {
+			return p, nil
+		}
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return ServerProfile{}, fmt.Errorf("profile %q not found in config", name)
+	}
+	return profile, nil
+}
+
+// YearOptionsFor returns the configured options for a year, or the zero
+// value if the config has none or is nil.
+func (c *Config) YearOptionsFor(year int) YearOptions {
+	if c == nil {
+		return YearOptions{}
+	}
+	return c.Years[year]
+}
@@ -0,0 +1,132 @@
+// Package render formats movie counts collected by the client into a
+// machine- or human-readable output format.
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Record is a single year's fetched movie count.
+type Record struct {
+	Year      int       `json:"year"`
+	Count     int       `json:"count"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Renderer writes a set of Records to w in a particular format.
+type Renderer interface {
+	Render(w io.Writer, records []Record) error
+}
+
+// New returns the Renderer registered for the given format name, e.g.
+// "text", "json", "csv", or "prom".
+func New(format string) (Renderer, error) {
+	switch format {
+	case "", "text":
+		return TextRenderer{}, nil
+	case "json":
+		return JSONRenderer{}, nil
+	case "csv":
+		return CSVRenderer{}, nil
+	case "prom":
+		return PromRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// sorted returns records ordered by year, leaving the input untouched.
+func sorted(records []Record) []Record {
+	out := make([]Record, len(records))
+	copy(out, records)
+	sort.Slice(out, func(i, j int) bool { return out[i].Year < out[j].Year })
+	return out
+}
+
+// TextRenderer renders the classic human-readable "Year X: N movies" lines.
+type TextRenderer struct{}
+
+func (TextRenderer) Render(w io.Writer, records []Record) error {
+	for _, r := range sorted(records) {
+		suffix := "s"
+		if r.Count == 1 {
+			suffix = ""
+		}
+		if _, err := fmt.Fprintf(w, "Year %d: %d movie%s\n", r.Year, r.Count, suffix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSONRenderer renders one JSON record per year, as a JSON array.
+type JSONRenderer struct{}
+
+func (JSONRenderer) Render(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sorted(records))
+}
+
+// CSVRenderer renders "year,count,fetched_at" rows with a header.
+type CSVRenderer struct{}
+
+func (CSVRenderer) Render(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"year", "count", "fetched_at"}); err != nil {
+		return err
+	}
+	for _, r := range sorted(records) {
+		row := []string{
+			fmt.Sprintf("%d", r.Year),
+			fmt.Sprintf("%d", r.Count),
+			r.FetchedAt.Format(time.RFC3339),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// PromRenderer renders a Prometheus text-format exposition, suitable for a
+// node_exporter textfile collector.
+type PromRenderer struct{}
+
+func (PromRenderer) Render(w io.Writer, records []Record) error {
+	if _, err := fmt.Fprintln(w, "# HELP sanger_movies_total Number of movies known to the Sanger server for a given year."); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "# TYPE sanger_movies_total gauge"); err != nil {
+		return err
+	}
+	var scrapedAt time.Time
+	for _, r := range sorted(records) {
+		if _, err := fmt.Fprintf(w, "sanger_movies_total{year=\"%d\"} %d\n", r.Year, r.Count); err != nil {
+			return err
+		}
+		if r.FetchedAt.After(scrapedAt) {
+			scrapedAt = r.FetchedAt
+		}
+	}
+	// With no records (every year failed, or was suppressed by
+	// ignore_if_empty), there's no fetch time to report; omit the gauge
+	// entirely rather than emit a zero-time Unix timestamp.
+	if len(records) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "# HELP sanger_scrape_timestamp_seconds Unix time the counts above were last fetched."); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "# TYPE sanger_scrape_timestamp_seconds gauge"); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "sanger_scrape_timestamp_seconds %d\n", scrapedAt.Unix())
+	return err
+}